@@ -0,0 +1,30 @@
+package backends
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+func init() {
+	Register("artifactory", newArtifactoryBackend)
+}
+
+// newArtifactoryBackend fetches artifacts from a JFrog Artifactory generic
+// repository, e.g. "artifactory://artifactory.example.com/artifactory/repo".
+func newArtifactoryBackend(u *url.URL) (Backend, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("artifactory mirror URL %q is missing a host", u.String())
+	}
+	baseURL := fmt.Sprintf("https://%s/%s", u.Host, strings.Trim(u.Path, "/"))
+
+	return &httpBackend{
+		client: http.DefaultClient,
+		label:  "artifactory mirror",
+		urlFor: func(key string) string {
+			return fmt.Sprintf("%s/%s", baseURL, path.Clean(key))
+		},
+	}, nil
+}
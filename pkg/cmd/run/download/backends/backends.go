@@ -0,0 +1,48 @@
+// Package backends implements pluggable long-term storage mirrors that
+// `gh run download` can fall back to when an artifact has already expired
+// from GitHub's own (comparatively short) retention window.
+package backends
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+)
+
+// ErrNotFound is returned by a Backend when the requested key does not exist,
+// so callers can distinguish "fall through to GitHub" from a hard failure.
+var ErrNotFound = errors.New("artifact not found in mirror")
+
+// Backend fetches previously-archived artifacts from a mirror by key, where
+// key is conventionally "{repo}/{run_id}/{artifact_name}".
+type Backend interface {
+	Fetch(key string) (io.ReadCloser, error)
+}
+
+// Factory constructs a Backend from the mirror URL it was registered for,
+// e.g. "s3://bucket/prefix".
+type Factory func(mirrorURL *url.URL) (Backend, error)
+
+var registry = map[string]Factory{}
+
+// Register associates a URL scheme (e.g. "s3") with a Backend factory. It is
+// meant to be called from the init() of each backend implementation.
+func Register(scheme string, factory Factory) {
+	registry[scheme] = factory
+}
+
+// Open parses rawURL and constructs the Backend registered for its scheme.
+func Open(rawURL string) (Backend, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid artifact mirror URL %q: %w", rawURL, err)
+	}
+
+	factory, ok := registry[u.Scheme]
+	if !ok {
+		return nil, fmt.Errorf("unsupported artifact mirror scheme %q", u.Scheme)
+	}
+
+	return factory(u)
+}
@@ -0,0 +1,91 @@
+package backends
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestOpen_UnknownScheme(t *testing.T) {
+	if _, err := Open("ftp://example.com/whatever"); err == nil {
+		t.Fatal("expected an error for an unregistered scheme")
+	}
+}
+
+func TestOpen_KnownScheme(t *testing.T) {
+	if _, err := Open("s3://bucket/prefix"); err != nil {
+		t.Fatalf("unexpected error opening a registered scheme: %v", err)
+	}
+}
+
+func TestHTTPBackend_Fetch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("payload"))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	mk := func(path string) *httpBackend {
+		return &httpBackend{
+			client: srv.Client(),
+			label:  "test backend",
+			urlFor: func(key string) string { return srv.URL + path },
+		}
+	}
+
+	t.Run("200 returns the body", func(t *testing.T) {
+		rc, err := mk("/ok").Fetch("key")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		defer rc.Close()
+		body, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("reading body: %v", err)
+		}
+		if string(body) != "payload" {
+			t.Errorf("body = %q, want %q", body, "payload")
+		}
+	})
+
+	t.Run("404 returns ErrNotFound", func(t *testing.T) {
+		_, err := mk("/missing").Fetch("key")
+		if err != ErrNotFound {
+			t.Errorf("err = %v, want ErrNotFound", err)
+		}
+	})
+
+	t.Run("500 returns a descriptive error", func(t *testing.T) {
+		_, err := mk("/boom").Fetch("key")
+		if err == nil {
+			t.Fatal("expected an error for a 500 response")
+		}
+	})
+}
+
+func TestS3Backend_URLFor(t *testing.T) {
+	b, err := newS3Backend(&url.URL{Host: "my-bucket", Path: "/prefix"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hb := b.(*httpBackend)
+	want := "https://my-bucket.s3.amazonaws.com/prefix/repo/1/artifact"
+	if got := hb.urlFor("repo/1/artifact"); got != want {
+		t.Errorf("urlFor = %q, want %q", got, want)
+	}
+}
+
+func TestS3Backend_RequiresHost(t *testing.T) {
+	if _, err := newS3Backend(&url.URL{}); err == nil {
+		t.Fatal("expected an error when the bucket/host is missing")
+	}
+}
@@ -0,0 +1,35 @@
+package backends
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// httpBackend fetches objects over plain HTTPS GET requests, translating a
+// 404 into ErrNotFound so callers can distinguish "try the next source" from
+// a hard failure. Backends that differ only in how they build the request
+// URL for a key (s3, nexus, artifactory, ...) can share this implementation.
+type httpBackend struct {
+	client  *http.Client
+	urlFor  func(key string) string
+	label   string // used in error messages, e.g. "s3 mirror"
+}
+
+func (b *httpBackend) Fetch(key string) (io.ReadCloser, error) {
+	resp, err := b.client.Get(b.urlFor(key))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d fetching %s from %s", resp.StatusCode, key, b.label)
+	}
+
+	return resp.Body, nil
+}
@@ -0,0 +1,30 @@
+package backends
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+func init() {
+	Register("nexus", newNexusBackend)
+}
+
+// newNexusBackend fetches artifacts from a Sonatype Nexus raw repository,
+// e.g. "nexus://nexus.example.com/repository/artifacts".
+func newNexusBackend(u *url.URL) (Backend, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("nexus mirror URL %q is missing a host", u.String())
+	}
+	baseURL := fmt.Sprintf("https://%s/%s", u.Host, strings.Trim(u.Path, "/"))
+
+	return &httpBackend{
+		client: http.DefaultClient,
+		label:  "nexus mirror",
+		urlFor: func(key string) string {
+			return fmt.Sprintf("%s/%s", baseURL, path.Clean(key))
+		},
+	}, nil
+}
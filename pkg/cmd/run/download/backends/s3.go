@@ -0,0 +1,37 @@
+package backends
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+func init() {
+	Register("s3", newS3Backend)
+}
+
+// newS3Backend fetches objects from an S3 bucket over HTTPS using the
+// bucket's virtual-hosted-style endpoint. Credentials are expected to be
+// handled by the bucket policy (e.g. a pre-signed proxy or public read access
+// to the archived-artifacts prefix); gh does not sign requests itself.
+func newS3Backend(u *url.URL) (Backend, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("s3 mirror URL %q is missing a bucket name", u.String())
+	}
+	bucket := u.Host
+	prefix := strings.Trim(u.Path, "/")
+
+	return &httpBackend{
+		client: http.DefaultClient,
+		label:  "s3 mirror",
+		urlFor: func(key string) string {
+			objectKey := key
+			if prefix != "" {
+				objectKey = path.Join(prefix, key)
+			}
+			return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", bucket, objectKey)
+		},
+	}, nil
+}
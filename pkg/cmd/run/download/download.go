@@ -3,9 +3,13 @@ package download
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 
 	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/pkg/cmd/run/download/backends"
 	"github.com/cli/cli/v2/pkg/cmd/run/shared"
 	"github.com/cli/cli/v2/pkg/cmdutil"
 	"github.com/cli/cli/v2/pkg/iostreams"
@@ -13,21 +17,61 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// artifactMirrorEnvVar overrides the configured mirror, e.g.
+// GH_ARTIFACT_MIRROR=s3://bucket/prefix.
+const artifactMirrorEnvVar = "GH_ARTIFACT_MIRROR"
+
+// maxParallelDownloads caps the number of artifacts downloaded at once, even if
+// the user asks for more, so a single run can't be used to exhaust file descriptors
+// or bandwidth on the machine running gh.
+const maxParallelDownloads = 10
+
 type DownloadOptions struct {
 	IO       *iostreams.IOStreams
 	Platform platform
 	Prompter iprompter
 
-	DoPrompt       bool
-	RunID          string
-	DestinationDir string
-	Names          []string
-	FilePatterns   []string
+	DoPrompt        bool
+	RunID           string
+	DestinationDir  string
+	Names           []string
+	FilePatterns    []string
+	Parallel        int
+	ContinueOnError bool
+	Retries         int
+	Verify          bool
+	Source          string
+	Quiet           bool
+
+	Attempt  int
+	Workflow string
+	Branch   string
+	Event    string
+	Created  string
+	Latest   bool
+}
+
+// Filter narrows down which run `ListRepo` should resolve artifacts for, when
+// the caller doesn't already know an exact run ID.
+type Filter struct {
+	Attempt  int
+	Workflow string
+	Branch   string
+	Event    string
+	Created  string
+	Latest   bool
 }
 
 type platform interface {
-	List(runID string) ([]shared.Artifact, error)
-	Download(url string, dir string) error
+	ListRun(runID string, attempt int) ([]shared.Artifact, error)
+	ListRepo(filter Filter) ([]shared.Artifact, error)
+	Download(runID string, artifact shared.Artifact, destDir string) error
+}
+
+// progressPlatform is implemented by platforms that can report fine-grained
+// transfer progress; not all platform implementations (e.g. in tests) bother.
+type progressPlatform interface {
+	DownloadWithProgress(runID string, artifact shared.Artifact, destDir string, onProgress func(read, total int64)) error
 }
 type iprompter interface {
 	MultiSelect(string, []string, []string) ([]int, error)
@@ -37,6 +81,8 @@ func NewCmdDownload(f *cmdutil.Factory, runF func(*DownloadOptions) error) *cobr
 	opts := &DownloadOptions{
 		IO:       f.IOStreams,
 		Prompter: f.Prompter,
+		Retries:  defaultRetries,
+		Source:   "auto",
 	}
 
 	cmd := &cobra.Command{
@@ -66,6 +112,9 @@ func NewCmdDownload(f *cmdutil.Factory, runF func(*DownloadOptions) error) *cobr
 
 			# Select artifacts to download interactively
 			$ gh run download
+
+			# Download artifacts from the latest run of a given workflow on a branch
+			$ gh run download --workflow release.yml --branch main --latest
 		`),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
@@ -75,6 +124,16 @@ func NewCmdDownload(f *cmdutil.Factory, runF func(*DownloadOptions) error) *cobr
 				opts.IO.CanPrompt() {
 				opts.DoPrompt = true
 			}
+			// A bare `gh run download` (no run ID, no --workflow/--branch/--event/--created)
+			// resolves to the newest run in the repository instead of failing with an
+			// ambiguous-match error; artifacts of older runs are not searched.
+			if opts.RunID == "" &&
+				opts.Workflow == "" &&
+				opts.Branch == "" &&
+				opts.Event == "" &&
+				opts.Created == "" {
+				opts.Latest = true
+			}
 			// support `-R, --repo` override
 			baseRepo, err := f.BaseRepo()
 			if err != nil {
@@ -84,9 +143,43 @@ func NewCmdDownload(f *cmdutil.Factory, runF func(*DownloadOptions) error) *cobr
 			if err != nil {
 				return err
 			}
+
+			switch opts.Source {
+			case "github", "mirror", "auto":
+			default:
+				return cmdutil.FlagErrorf("--source must be one of \"github\", \"mirror\", or \"auto\"")
+			}
+
+			var mirror backends.Backend
+			if opts.Source != "github" {
+				mirrorURL := os.Getenv(artifactMirrorEnvVar)
+				if mirrorURL == "" {
+					if cfg, err := f.Config(); err == nil {
+						if v, err := cfg.Get(baseRepo.RepoHost(), "artifact_mirror"); err == nil {
+							mirrorURL = v
+						}
+					}
+				}
+				if mirrorURL != "" {
+					if mirror, err = backends.Open(mirrorURL); err != nil {
+						return err
+					}
+				} else if opts.Source == "mirror" {
+					return cmdutil.FlagErrorf("--source=mirror requires %s (or the artifact_mirror config key) to be set", artifactMirrorEnvVar)
+				}
+			}
+
 			opts.Platform = &apiPlatform{
-				client: httpClient,
-				repo:   baseRepo,
+				client:  httpClient,
+				repo:    baseRepo,
+				Retries: opts.Retries,
+				Verify:  opts.Verify,
+				Source:  opts.Source,
+				Mirror:  mirror,
+			}
+
+			if opts.Parallel < 1 || opts.Parallel > maxParallelDownloads {
+				return cmdutil.FlagErrorf("--parallel must be between 1 and %d", maxParallelDownloads)
 			}
 
 			if runF != nil {
@@ -99,13 +192,37 @@ func NewCmdDownload(f *cmdutil.Factory, runF func(*DownloadOptions) error) *cobr
 	cmd.Flags().StringVarP(&opts.DestinationDir, "dir", "D", ".", "The directory to download artifacts into")
 	cmd.Flags().StringArrayVarP(&opts.Names, "name", "n", nil, "Download artifacts that match any of the given names")
 	cmd.Flags().StringArrayVarP(&opts.FilePatterns, "pattern", "p", nil, "Download artifacts that match a glob pattern")
+	cmd.Flags().IntVarP(&opts.Parallel, "parallel", "", 3, "Number of artifacts to download at once")
+	cmd.Flags().BoolVar(&opts.ContinueOnError, "continue-on-error", false, "Keep downloading remaining artifacts after one fails")
+	cmd.Flags().BoolVar(&opts.Verify, "verify", false, "Verify the checksum of each downloaded artifact entry")
+	cmd.Flags().StringVar(&opts.Source, "source", "auto", "Where to fetch artifacts from: {github|mirror|auto}")
+	cmd.Flags().BoolVarP(&opts.Quiet, "quiet", "q", false, "Suppress per-artifact progress bars in favor of a single spinner")
+	cmd.Flags().IntVar(&opts.Attempt, "attempt", 0, "Select a specific re-run attempt (defaults to the latest attempt)")
+	cmd.Flags().StringVar(&opts.Workflow, "workflow", "", "Filter by workflow file name or name")
+	cmd.Flags().StringVar(&opts.Branch, "branch", "", "Filter by branch")
+	cmd.Flags().StringVar(&opts.Event, "event", "", "Filter by triggering event")
+	cmd.Flags().StringVar(&opts.Created, "created", "", "Filter by creation date, using the same syntax as `gh run list`")
+	cmd.Flags().BoolVar(&opts.Latest, "latest", false, "Resolve to the most recent run matching the given filters")
 
 	return cmd
 }
 
 func runDownload(opts *DownloadOptions) error {
 	opts.IO.StartProgressIndicator()
-	artifacts, err := opts.Platform.List(opts.RunID)
+	var artifacts []shared.Artifact
+	var err error
+	if opts.RunID != "" {
+		artifacts, err = opts.Platform.ListRun(opts.RunID, opts.Attempt)
+	} else {
+		artifacts, err = opts.Platform.ListRepo(Filter{
+			Attempt:  opts.Attempt,
+			Workflow: opts.Workflow,
+			Branch:   opts.Branch,
+			Event:    opts.Event,
+			Created:  opts.Created,
+			Latest:   opts.Latest,
+		})
+	}
 	opts.IO.StopProgressIndicator()
 	if err != nil {
 		return fmt.Errorf("error fetching artifacts: %w", err)
@@ -148,18 +265,16 @@ func runDownload(opts *DownloadOptions) error {
 		}
 	}
 
-	opts.IO.StartProgressIndicator()
-	defer opts.IO.StopProgressIndicator()
-
-	// track downloaded artifacts and avoid re-downloading any of the same name, isolate if multiple artifacts
-	downloaded := set.NewStringSet()
+	// de-duplicate against artifacts of the same name and work out which ones to fetch
+	seen := set.NewStringSet()
 	isolateArtifacts := isolateArtifacts(wantNames, wantPatterns)
 
+	var wanted []shared.Artifact
 	for _, a := range artifacts {
 		if a.Expired {
 			continue
 		}
-		if downloaded.Contains(a.Name) {
+		if seen.Contains(a.Name) {
 			continue
 		}
 		if len(wantNames) > 0 || len(wantPatterns) > 0 {
@@ -167,25 +282,106 @@ func runDownload(opts *DownloadOptions) error {
 				continue
 			}
 		}
+		seen.Add(a.Name)
+		wanted = append(wanted, a)
+	}
 
-		destDir := opts.DestinationDir
-		if isolateArtifacts {
-			destDir = filepath.Join(destDir, a.Name)
-		}
+	if len(wanted) == 0 {
+		return errors.New("no artifact matches any of the names or patterns provided")
+	}
 
-		if !filepathDescendsFrom(destDir, opts.DestinationDir) {
-			return fmt.Errorf("error downloading %s: would result in path traversal", a.Name)
+	useBars := opts.IO.IsStdoutTTY() && !opts.Quiet
+	progressPf, supportsBars := opts.Platform.(progressPlatform)
+	useBars = useBars && supportsBars
+
+	var bars *artifactProgress
+	var mu sync.Mutex
+	numDone := 0
+	reportProgress := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		numDone++
+		if !useBars {
+			opts.IO.StopProgressIndicator()
+			opts.IO.StartProgressIndicatorWithLabel(fmt.Sprintf("Downloading artifacts (%d/%d)", numDone, len(wanted)))
 		}
+	}
 
-		err := opts.Platform.Download(a.DownloadURL, destDir)
-		if err != nil {
-			return fmt.Errorf("error downloading %s: %w", a.Name, err)
-		}
-		downloaded.Add(a.Name)
+	if useBars {
+		bars = newArtifactProgress(opts.IO)
+		bars.Start()
+		defer bars.Stop()
+	} else {
+		opts.IO.StartProgressIndicatorWithLabel(fmt.Sprintf("Downloading artifacts (0/%d)", len(wanted)))
+		defer opts.IO.StopProgressIndicator()
 	}
 
-	if downloaded.Len() == 0 {
-		return errors.New("no artifact matches any of the names or patterns provided")
+	queue := make(chan shared.Artifact)
+	errs := make(chan error, len(wanted))
+	var stopped int32
+
+	var wg sync.WaitGroup
+	workers := opts.Parallel
+	if workers > len(wanted) {
+		workers = len(wanted)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for a := range queue {
+				if atomic.LoadInt32(&stopped) != 0 {
+					continue
+				}
+
+				destDir := opts.DestinationDir
+				if isolateArtifacts {
+					destDir = filepath.Join(destDir, a.Name)
+				}
+
+				if !filepathDescendsFrom(destDir, opts.DestinationDir) {
+					errs <- fmt.Errorf("error downloading %s: would result in path traversal", a.Name)
+					if !opts.ContinueOnError {
+						atomic.StoreInt32(&stopped, 1)
+					}
+					continue
+				}
+
+				var err error
+				if useBars {
+					err = progressPf.DownloadWithProgress(opts.RunID, a, destDir, bars.Track(a.Name))
+				} else {
+					err = opts.Platform.Download(opts.RunID, a, destDir)
+				}
+				if err != nil {
+					errs <- fmt.Errorf("error downloading %s: %w", a.Name, err)
+					if !opts.ContinueOnError {
+						atomic.StoreInt32(&stopped, 1)
+					}
+					continue
+				}
+
+				reportProgress()
+			}
+		}()
+	}
+
+	go func() {
+		for _, a := range wanted {
+			queue <- a
+		}
+		close(queue)
+	}()
+
+	wg.Wait()
+	close(errs)
+
+	var errList []error
+	for err := range errs {
+		errList = append(errList, err)
+	}
+	if len(errList) > 0 {
+		return errors.Join(errList...)
 	}
 
 	return nil
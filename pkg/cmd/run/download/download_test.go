@@ -0,0 +1,123 @@
+package download
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/cli/cli/v2/pkg/cmd/run/shared"
+	"github.com/cli/cli/v2/pkg/iostreams"
+)
+
+func TestIsolateArtifacts(t *testing.T) {
+	tests := []struct {
+		name     string
+		names    []string
+		patterns []string
+		want     bool
+	}{
+		{name: "no selection downloads everything isolated", want: true},
+		{name: "a single named artifact is not isolated", names: []string{"a"}, want: false},
+		{name: "multiple named artifacts are isolated", names: []string{"a", "b"}, want: true},
+		{name: "patterns are always isolated", patterns: []string{"*.txt"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isolateArtifacts(tt.names, tt.patterns); got != tt.want {
+				t.Errorf("isolateArtifacts(%v, %v) = %v, want %v", tt.names, tt.patterns, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchAnyPattern(t *testing.T) {
+	if !matchAnyPattern([]string{"*.zip"}, "build.zip") {
+		t.Error("expected build.zip to match *.zip")
+	}
+	if matchAnyPattern([]string{"*.zip"}, "build.tar") {
+		t.Error("expected build.tar not to match *.zip")
+	}
+}
+
+type fakePlatform struct {
+	artifacts []shared.Artifact
+	failNames map[string]bool
+
+	mu         sync.Mutex
+	downloaded map[string]bool
+}
+
+func (f *fakePlatform) ListRun(runID string, attempt int) ([]shared.Artifact, error) {
+	return f.artifacts, nil
+}
+
+func (f *fakePlatform) ListRepo(filter Filter) ([]shared.Artifact, error) {
+	return f.artifacts, nil
+}
+
+func (f *fakePlatform) Download(runID string, artifact shared.Artifact, destDir string) error {
+	if f.failNames[artifact.Name] {
+		return fmt.Errorf("simulated failure for %s", artifact.Name)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.downloaded == nil {
+		f.downloaded = map[string]bool{}
+	}
+	f.downloaded[artifact.Name] = true
+	return nil
+}
+
+func TestRunDownload_ContinueOnError(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+
+	fake := &fakePlatform{
+		artifacts: []shared.Artifact{
+			{Name: "good-a", DownloadURL: "http://example.com/a"},
+			{Name: "bad", DownloadURL: "http://example.com/bad"},
+			{Name: "good-b", DownloadURL: "http://example.com/b"},
+		},
+		failNames: map[string]bool{"bad": true},
+	}
+
+	opts := &DownloadOptions{
+		IO:              io,
+		Platform:        fake,
+		Parallel:        3,
+		ContinueOnError: true,
+		DestinationDir:  t.TempDir(),
+	}
+
+	err := runDownload(opts)
+	if err == nil {
+		t.Fatal("expected an error to be returned for the failed artifact")
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !fake.downloaded["good-a"] || !fake.downloaded["good-b"] {
+		t.Errorf("expected both good artifacts to be downloaded despite the failure, got %v", fake.downloaded)
+	}
+}
+
+func TestRunDownload_FailFastStillReportsError(t *testing.T) {
+	io, _, _, _ := iostreams.Test()
+
+	fake := &fakePlatform{
+		artifacts: []shared.Artifact{
+			{Name: "bad", DownloadURL: "http://example.com/bad"},
+		},
+		failNames: map[string]bool{"bad": true},
+	}
+
+	opts := &DownloadOptions{
+		IO:             io,
+		Platform:       fake,
+		Parallel:       1,
+		DestinationDir: t.TempDir(),
+	}
+
+	if err := runDownload(opts); err == nil {
+		t.Fatal("expected an error for the failed artifact")
+	}
+}
@@ -0,0 +1,356 @@
+package download
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/run/download/backends"
+	"github.com/cli/cli/v2/pkg/cmd/run/shared"
+)
+
+const (
+	defaultRetries = 3
+	partSuffix     = ".part"
+)
+
+type apiPlatform struct {
+	client  *http.Client
+	repo    ghrepo.Interface
+	Retries int
+	Verify  bool
+
+	// Source selects where artifacts are fetched from: "github", "mirror", or
+	// "auto" (try Mirror first, falling back to the GitHub API).
+	Source string
+	Mirror backends.Backend
+}
+
+// ListRun returns the artifacts attached to a specific run, optionally
+// narrowed to a single re-run attempt (0 means the latest attempt).
+func (p *apiPlatform) ListRun(runID string, attempt int) ([]shared.Artifact, error) {
+	if attempt > 0 {
+		return shared.ListArtifactsForAttempt(p.client, p.repo, runID, attempt)
+	}
+	return shared.ListArtifacts(p.client, p.repo, runID)
+}
+
+// ListRepo resolves the run matching filter server-side, then returns its
+// artifacts. Pushing the filtering into the runs-list API call avoids having
+// to page through `gh run list` results client-side.
+func (p *apiPlatform) ListRepo(filter Filter) ([]shared.Artifact, error) {
+	runID, err := p.resolveRun(filter)
+	if err != nil {
+		return nil, err
+	}
+	return p.ListRun(runID, filter.Attempt)
+}
+
+// resolveRun finds the runs matching filter, newest first. More than one
+// match is only accepted when filter.Latest is set, so users who haven't
+// opted into "just grab the newest one" get a chance to narrow things down
+// instead of silently downloading the wrong run.
+func (p *apiPlatform) resolveRun(filter Filter) (string, error) {
+	runIDs, err := shared.FindRunIDs(p.client, p.repo, shared.FilterOptions{
+		WorkflowSelector: filter.Workflow,
+		Branch:           filter.Branch,
+		Event:            filter.Event,
+		Created:          filter.Created,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(runIDs) == 0 {
+		return "", errors.New("no run found matching the given filters")
+	}
+	if len(runIDs) > 1 && !filter.Latest {
+		return "", fmt.Errorf("%d runs match the given filters; pass --latest to use the most recent one, or narrow the filters further", len(runIDs))
+	}
+
+	return runIDs[0], nil
+}
+
+func (p *apiPlatform) Download(runID string, artifact shared.Artifact, destDir string) error {
+	return p.download(runID, artifact, destDir, nil)
+}
+
+// DownloadWithProgress behaves like Download but reports cumulative bytes
+// read (and total size, when known) to onProgress as the transfer proceeds.
+func (p *apiPlatform) DownloadWithProgress(runID string, artifact shared.Artifact, destDir string, onProgress func(read, total int64)) error {
+	return p.download(runID, artifact, destDir, onProgress)
+}
+
+func (p *apiPlatform) download(runID string, artifact shared.Artifact, destDir string, onProgress func(read, total int64)) error {
+	if p.Source != "github" && p.Mirror != nil {
+		key := fmt.Sprintf("%s/%s/%s", ghrepo.FullName(p.repo), runID, artifact.Name)
+		err := p.downloadFromMirror(key, destDir, onProgress)
+		if err == nil {
+			return nil
+		}
+		if p.Source == "mirror" {
+			return err
+		}
+		if !errors.Is(err, backends.ErrNotFound) {
+			return err
+		}
+		// auto: mirror doesn't have it, fall through to the GitHub API below.
+	}
+
+	return p.downloadFromGitHub(artifact.DownloadURL, destDir, onProgress)
+}
+
+func (p *apiPlatform) downloadFromMirror(key, destDir string, onProgress func(read, total int64)) error {
+	rc, err := p.Mirror.Fetch(key)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+	partPath := filepath.Join(destDir, "artifact.zip"+partSuffix)
+
+	f, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	var r io.Reader = rc
+	if onProgress != nil {
+		// The mirror interface doesn't expose a size up front.
+		r = NewProgressReader(rc, -1, onProgress)
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(partPath)
+		return err
+	}
+	f.Close()
+
+	if err := extractZip(partPath, destDir, p.Verify); err != nil {
+		os.Remove(partPath)
+		return err
+	}
+	os.Remove(partPath)
+	return nil
+}
+
+func (p *apiPlatform) downloadFromGitHub(url string, destDir string, onProgress func(read, total int64)) error {
+	retries := p.Retries
+	if retries <= 0 {
+		retries = defaultRetries
+	}
+
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return err
+	}
+	partPath := filepath.Join(destDir, "artifact.zip"+partSuffix)
+
+	var lastErr error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff(attempt))
+		}
+
+		if err := downloadToFile(p.client, url, partPath, onProgress); err != nil {
+			lastErr = err
+			if !isTransient(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := extractZip(partPath, destDir, p.Verify); err != nil {
+			os.Remove(partPath)
+			return err
+		}
+
+		os.Remove(partPath)
+		return nil
+	}
+
+	return fmt.Errorf("giving up after %d attempts: %w", retries+1, lastErr)
+}
+
+// downloadToFile streams url into dest, resuming a partially-downloaded dest
+// via a Range request when the server supports it, and reporting progress
+// (total bytes already on disk plus whatever is newly read) to onProgress.
+func downloadToFile(client *http.Client, url, dest string, onProgress func(read, total int64)) error {
+	var startAt int64
+	if fi, err := os.Stat(dest); err == nil {
+		startAt = fi.Size()
+	}
+
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	if startAt > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", startAt))
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	resuming := startAt > 0 && resp.StatusCode == http.StatusPartialContent
+	if !resuming && startAt > 0 {
+		// Server ignored our Range request; start over.
+		startAt = 0
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return fmt.Errorf("unexpected status %d while downloading artifact", resp.StatusCode)
+	}
+
+	flag := os.O_CREATE | os.O_WRONLY
+	if resuming {
+		flag |= os.O_APPEND
+	} else {
+		flag |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(dest, flag, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var body io.Reader = resp.Body
+	if onProgress != nil {
+		total := resp.ContentLength
+		if total > 0 {
+			total += startAt
+		}
+		body = NewProgressReader(resp.Body, total, func(read, total int64) {
+			onProgress(startAt+read, total)
+		})
+	}
+
+	_, err = io.Copy(f, body)
+	return err
+}
+
+// extractZip extracts zipPath into a scratch directory next to destDir and
+// only moves the result into destDir once every entry has been written and
+// verified. This keeps a corrupted artifact (a bad CRC partway through) from
+// leaving partially-extracted files behind in the caller's destination.
+func extractZip(zipPath, destDir string, verify bool) error {
+	zr, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	return extractZipFiles(zr.File, destDir, verify)
+}
+
+// extractZipFiles does the actual extraction work against a slice of
+// *zip.File, independent of where they came from, so tests can exercise it
+// against a deliberately corrupted in-memory archive.
+func extractZipFiles(files []*zip.File, destDir string, verify bool) error {
+	scratchParent := filepath.Dir(destDir)
+	if err := os.MkdirAll(scratchParent, 0755); err != nil {
+		return err
+	}
+	scratchDir, err := os.MkdirTemp(scratchParent, ".gh-artifact-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(scratchDir)
+
+	for _, zf := range files {
+		path := filepath.Join(scratchDir, zf.Name)
+		if !filepathDescendsFrom(path, scratchDir) {
+			return fmt.Errorf("error extracting %s: would result in path traversal", zf.Name)
+		}
+
+		if zf.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+
+		if err := extractZipEntry(zf, path, verify); err != nil {
+			return err
+		}
+	}
+
+	return moveTree(scratchDir, destDir)
+}
+
+// moveTree moves the contents of src into dst, creating dst if needed.
+func moveTree(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.Rename(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func extractZipEntry(zf *zip.File, dest string, verify bool) error {
+	rc, err := zf.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	f, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, zf.Mode())
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var w io.Writer = f
+	hasher := crc32.NewIEEE()
+	if verify {
+		w = io.MultiWriter(f, hasher)
+	}
+
+	if _, err := io.Copy(w, rc); err != nil {
+		return err
+	}
+
+	if verify && hasher.Sum32() != zf.CRC32 {
+		return fmt.Errorf("checksum mismatch for %s: corrupted download", zf.Name)
+	}
+
+	return nil
+}
+
+func backoff(attempt int) time.Duration {
+	d := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+	if d > 30*time.Second {
+		d = 30 * time.Second
+	}
+	return d
+}
+
+func isTransient(err error) bool {
+	// A 4xx status is treated as permanent. Everything else (a 5xx status, or a
+	// network-level failure such as a timeout or connection reset) is retried.
+	msg := err.Error()
+	return !strings.Contains(msg, "unexpected status 4")
+}
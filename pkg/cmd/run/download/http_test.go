@@ -0,0 +1,120 @@
+package download
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	if got := backoff(0); got != 1*time.Second {
+		t.Errorf("backoff(0) = %v, want 1s", got)
+	}
+	if got := backoff(3); got != 8*time.Second {
+		t.Errorf("backoff(3) = %v, want 8s", got)
+	}
+	if got := backoff(10); got != 30*time.Second {
+		t.Errorf("backoff(10) = %v, want capped at 30s", got)
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	if isTransient(&statusError{"unexpected status 404 while downloading artifact"}) {
+		t.Error("expected a 4xx status error to be treated as non-transient")
+	}
+	if !isTransient(&statusError{"connection reset by peer"}) {
+		t.Error("expected a network error to be treated as transient")
+	}
+}
+
+type statusError struct{ msg string }
+
+func (e *statusError) Error() string { return e.msg }
+
+// buildZip constructs an in-memory zip archive with the given name/contents
+// pairs, returning its bytes.
+func buildZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, contents := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("creating entry %s: %v", name, err)
+		}
+		if _, err := w.Write([]byte(contents)); err != nil {
+			t.Fatalf("writing entry %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractZipFiles_ChecksumMismatchLeavesDestClean(t *testing.T) {
+	raw := buildZip(t, map[string]string{
+		"good.txt": "this entry is fine",
+		"bad.txt":  "this entry will be reported as corrupted",
+	})
+
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		t.Fatalf("reading back zip: %v", err)
+	}
+
+	// Corrupt the CRC of one entry to simulate a bad download, the same way a
+	// truncated or bit-flipped artifact would fail verification.
+	for _, zf := range zr.File {
+		if zf.Name == "bad.txt" {
+			zf.CRC32 ^= 0xffffffff
+		}
+	}
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+
+	err = extractZipFiles(zr.File, destDir, true)
+	if err == nil {
+		t.Fatal("expected a checksum mismatch error")
+	}
+
+	if _, statErr := os.Stat(destDir); !os.IsNotExist(statErr) {
+		t.Errorf("expected destDir to not exist after a failed extraction, got stat err: %v", statErr)
+	}
+}
+
+func TestExtractZipFiles_AllEntriesValid(t *testing.T) {
+	raw := buildZip(t, map[string]string{
+		"a.txt":        "alpha",
+		"nested/b.txt": "beta",
+	})
+
+	zr, err := zip.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		t.Fatalf("reading back zip: %v", err)
+	}
+
+	destDir := filepath.Join(t.TempDir(), "dest")
+	if err := extractZipFiles(zr.File, destDir, true); err != nil {
+		t.Fatalf("unexpected error extracting valid zip: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(destDir, "a.txt"))
+	if err != nil {
+		t.Fatalf("reading extracted a.txt: %v", err)
+	}
+	if string(got) != "alpha" {
+		t.Errorf("a.txt = %q, want %q", got, "alpha")
+	}
+
+	got, err = os.ReadFile(filepath.Join(destDir, "nested", "b.txt"))
+	if err != nil {
+		t.Fatalf("reading extracted nested/b.txt: %v", err)
+	}
+	if string(got) != "beta" {
+		t.Errorf("nested/b.txt = %q, want %q", got, "beta")
+	}
+}
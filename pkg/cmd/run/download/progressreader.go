@@ -0,0 +1,148 @@
+package download
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/iostreams"
+)
+
+// ProgressReader wraps an io.Reader, reporting cumulative bytes read and the
+// (possibly unknown, i.e. <= 0) total size via onRead on every Read that
+// returns data.
+type ProgressReader struct {
+	io.Reader
+	total  int64
+	read   int64
+	onRead func(read, total int64)
+}
+
+func NewProgressReader(r io.Reader, total int64, onRead func(read, total int64)) *ProgressReader {
+	return &ProgressReader{Reader: r, total: total, onRead: onRead}
+}
+
+func (r *ProgressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		if r.onRead != nil {
+			r.onRead(r.read, r.total)
+		}
+	}
+	return n, err
+}
+
+// artifactProgress renders one line per tracked artifact download, showing
+// bytes transferred, total size, transfer rate, and ETA; finished downloads
+// keep their line until Stop. It's only meant to be
+// used when the IO is attached to a TTY; callers should fall back to the
+// plain spinner otherwise.
+type artifactProgress struct {
+	io *iostreams.IOStreams
+
+	mu     sync.Mutex
+	bars   map[string]*progressBar
+	order  []string
+	ticker *time.Ticker
+	done   chan struct{}
+	lines  int
+}
+
+type progressBar struct {
+	total   int64
+	read    int64
+	started time.Time
+}
+
+func newArtifactProgress(io *iostreams.IOStreams) *artifactProgress {
+	return &artifactProgress{io: io, bars: map[string]*progressBar{}}
+}
+
+func (m *artifactProgress) Start() {
+	m.done = make(chan struct{})
+	m.ticker = time.NewTicker(200 * time.Millisecond)
+	go func() {
+		for {
+			select {
+			case <-m.ticker.C:
+				m.render()
+			case <-m.done:
+				return
+			}
+		}
+	}()
+}
+
+func (m *artifactProgress) Stop() {
+	m.ticker.Stop()
+	close(m.done)
+	m.render()
+}
+
+// Track registers a new in-flight artifact and returns the callback to pass
+// to a ProgressReader for it.
+func (m *artifactProgress) Track(name string) func(read, total int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bars[name] = &progressBar{started: time.Now()}
+	m.order = append(m.order, name)
+
+	return func(read, total int64) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		bar := m.bars[name]
+		bar.read = read
+		if total > 0 {
+			bar.total = total
+		}
+	}
+}
+
+func (m *artifactProgress) render() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	for i := 0; i < m.lines; i++ {
+		fmt.Fprint(&b, "\033[1A\033[2K")
+	}
+	for _, name := range m.order {
+		bar := m.bars[name]
+		elapsed := time.Since(bar.started).Seconds()
+		var rate float64
+		if elapsed > 0 {
+			rate = float64(bar.read) / elapsed
+		}
+		pct := "?"
+		eta := "?"
+		if bar.total > 0 {
+			pct = fmt.Sprintf("%.0f%%", float64(bar.read)/float64(bar.total)*100)
+			if rate > 0 && bar.total > bar.read {
+				secs := float64(bar.total-bar.read) / rate
+				eta = time.Duration(secs * float64(time.Second)).Round(time.Second).String()
+			}
+		}
+		fmt.Fprintf(&b, "%s: %s of %s (%s) %s/s ETA %s\n", name, humanBytes(bar.read), humanBytes(bar.total), pct, humanBytes(int64(rate)), eta)
+	}
+	m.lines = len(m.order)
+	fmt.Fprint(m.io.ErrOut, b.String())
+}
+
+func humanBytes(n int64) string {
+	if n <= 0 {
+		return "?"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
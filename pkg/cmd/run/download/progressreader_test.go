@@ -0,0 +1,59 @@
+package download
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestProgressReader_ReportsCumulativeBytes(t *testing.T) {
+	src := strings.Repeat("x", 100)
+	var gotRead, gotTotal int64
+	calls := 0
+
+	pr := NewProgressReader(strings.NewReader(src), 100, func(read, total int64) {
+		calls++
+		gotRead = read
+		gotTotal = total
+	})
+
+	buf := make([]byte, 10)
+	total := 0
+	for {
+		n, err := pr.Read(buf)
+		total += n
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected read error: %v", err)
+		}
+	}
+
+	if total != 100 {
+		t.Fatalf("read %d bytes, want 100", total)
+	}
+	if calls == 0 {
+		t.Fatal("expected onRead to be called at least once")
+	}
+	if gotRead != 100 {
+		t.Errorf("final reported read = %d, want 100", gotRead)
+	}
+	if gotTotal != 100 {
+		t.Errorf("final reported total = %d, want 100", gotTotal)
+	}
+}
+
+func TestProgressReader_UnknownTotal(t *testing.T) {
+	var gotTotal int64 = -99
+	pr := NewProgressReader(strings.NewReader("hello"), -1, func(read, total int64) {
+		gotTotal = total
+	})
+
+	if _, err := io.ReadAll(pr); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotTotal != -1 {
+		t.Errorf("total = %d, want -1 (unknown)", gotTotal)
+	}
+}
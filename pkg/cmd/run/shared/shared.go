@@ -0,0 +1,135 @@
+// Package shared provides types and helpers shared by the `gh run`
+// subcommands for listing workflow runs and their artifacts.
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cli/cli/v2/internal/ghrepo"
+)
+
+// Artifact represents an artifact uploaded during a workflow run.
+type Artifact struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"archive_download_url"`
+	Expired     bool   `json:"expired"`
+}
+
+type artifactsPayload struct {
+	Artifacts []Artifact `json:"artifacts"`
+}
+
+// ListArtifacts returns the artifacts attached to the latest attempt of runID.
+func ListArtifacts(client *http.Client, repo ghrepo.Interface, runID string) ([]Artifact, error) {
+	path := fmt.Sprintf("repos/%s/actions/runs/%s/artifacts", ghrepo.FullName(repo), runID)
+	return listArtifacts(client, repo, path)
+}
+
+// ListArtifactsForAttempt returns the artifacts attached to a specific re-run
+// attempt of runID.
+func ListArtifactsForAttempt(client *http.Client, repo ghrepo.Interface, runID string, attempt int) ([]Artifact, error) {
+	path := fmt.Sprintf("repos/%s/actions/runs/%s/attempts/%d/artifacts", ghrepo.FullName(repo), runID, attempt)
+	return listArtifacts(client, repo, path)
+}
+
+func listArtifacts(client *http.Client, repo ghrepo.Interface, path string) ([]Artifact, error) {
+	req, err := http.NewRequest("GET", APIURL(repo, path), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d fetching artifacts", resp.StatusCode)
+	}
+
+	var payload artifactsPayload
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+	return payload.Artifacts, nil
+}
+
+// FilterOptions narrows down the runs FindRunIDs considers a match.
+type FilterOptions struct {
+	WorkflowSelector string
+	Branch           string
+	Event            string
+	Created          string
+}
+
+type runsPayload struct {
+	WorkflowRuns []struct {
+		DatabaseID int64 `json:"id"`
+	} `json:"workflow_runs"`
+}
+
+// FindRunIDs returns the IDs of the runs matching filter, newest first.
+func FindRunIDs(client *http.Client, repo ghrepo.Interface, filter FilterOptions) ([]string, error) {
+	path := fmt.Sprintf("repos/%s/actions/runs", ghrepo.FullName(repo))
+	if filter.WorkflowSelector != "" {
+		path = fmt.Sprintf("repos/%s/actions/workflows/%s/runs", ghrepo.FullName(repo), url.PathEscape(filter.WorkflowSelector))
+	}
+
+	q := url.Values{}
+	if filter.Branch != "" {
+		q.Set("branch", filter.Branch)
+	}
+	if filter.Event != "" {
+		q.Set("event", filter.Event)
+	}
+	if filter.Created != "" {
+		q.Set("created", filter.Created)
+	}
+
+	reqURL := APIURL(repo, path)
+	if encoded := q.Encode(); encoded != "" {
+		reqURL += "?" + encoded
+	}
+
+	req, err := http.NewRequest("GET", reqURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unexpected status %d listing runs", resp.StatusCode)
+	}
+
+	var payload runsPayload
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(payload.WorkflowRuns))
+	for i, r := range payload.WorkflowRuns {
+		ids[i] = strconv.FormatInt(r.DatabaseID, 10)
+	}
+	return ids, nil
+}
+
+// APIURL builds the REST API URL for path ("repos/owner/name/...", no leading
+// slash) against repo, accounting for GitHub Enterprise Server hosts. It's
+// exported so other `gh run` subcommands (e.g. `gh run upload`) that need the
+// same github.com-vs-GHES branching don't have to reimplement it.
+func APIURL(repo ghrepo.Interface, path string) string {
+	if repo.RepoHost() == ghrepo.DefaultHostname || repo.RepoHost() == "" {
+		return "https://api.github.com/" + path
+	}
+	return fmt.Sprintf("https://%s/api/v3/%s", repo.RepoHost(), path)
+}
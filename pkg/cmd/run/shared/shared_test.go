@@ -0,0 +1,132 @@
+package shared
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/cli/cli/v2/internal/ghrepo"
+)
+
+// fakeTransport lets tests stub HTTP responses without depending on a real
+// network listener or a particular URL scheme.
+type fakeTransport struct {
+	do func(*http.Request) (*http.Response, error)
+}
+
+func (t *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.do(req)
+}
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(body)),
+		Header:     http.Header{},
+	}
+}
+
+func TestListArtifacts(t *testing.T) {
+	var gotPath string
+	client := &http.Client{Transport: &fakeTransport{do: func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		return jsonResponse(`{"artifacts":[{"name":"build","archive_download_url":"https://example.com/build.zip","expired":false}]}`), nil
+	}}}
+
+	artifacts, err := ListArtifacts(client, ghrepo.New("owner", "repo"), "123")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(artifacts) != 1 || artifacts[0].Name != "build" {
+		t.Errorf("artifacts = %+v, want a single \"build\" artifact", artifacts)
+	}
+	if gotPath != "/repos/owner/repo/actions/runs/123/artifacts" {
+		t.Errorf("request path = %q", gotPath)
+	}
+}
+
+func TestListArtifactsForAttempt(t *testing.T) {
+	var gotPath string
+	client := &http.Client{Transport: &fakeTransport{do: func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		return jsonResponse(`{"artifacts":[]}`), nil
+	}}}
+
+	if _, err := ListArtifactsForAttempt(client, ghrepo.New("owner", "repo"), "123", 2); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if gotPath != "/repos/owner/repo/actions/runs/123/attempts/2/artifacts" {
+		t.Errorf("request path = %q", gotPath)
+	}
+}
+
+func TestListArtifacts_ErrorStatus(t *testing.T) {
+	client := &http.Client{Transport: &fakeTransport{do: func(req *http.Request) (*http.Response, error) {
+		resp := jsonResponse(`{}`)
+		resp.StatusCode = http.StatusInternalServerError
+		return resp, nil
+	}}}
+
+	if _, err := ListArtifacts(client, ghrepo.New("owner", "repo"), "123"); err == nil {
+		t.Fatal("expected an error for a 500 response")
+	}
+}
+
+func TestFindRunIDs(t *testing.T) {
+	var gotPath, gotQuery string
+	client := &http.Client{Transport: &fakeTransport{do: func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotQuery = req.URL.RawQuery
+		return jsonResponse(`{"workflow_runs":[{"id":2},{"id":1}]}`), nil
+	}}}
+
+	ids, err := FindRunIDs(client, ghrepo.New("owner", "repo"), FilterOptions{
+		WorkflowSelector: "release.yml",
+		Branch:           "main",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != "2" || ids[1] != "1" {
+		t.Errorf("ids = %v, want [2 1] preserving server order", ids)
+	}
+	if gotPath != "/repos/owner/repo/actions/workflows/release.yml/runs" {
+		t.Errorf("request path = %q", gotPath)
+	}
+	if gotQuery != "branch=main" {
+		t.Errorf("request query = %q", gotQuery)
+	}
+}
+
+func TestFindRunIDs_NoFilters(t *testing.T) {
+	var gotPath, gotQuery string
+	client := &http.Client{Transport: &fakeTransport{do: func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotQuery = req.URL.RawQuery
+		return jsonResponse(`{"workflow_runs":[{"id":42}]}`), nil
+	}}}
+
+	ids, err := FindRunIDs(client, ghrepo.New("owner", "repo"), FilterOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "42" {
+		t.Errorf("ids = %v, want [42]", ids)
+	}
+	if gotPath != "/repos/owner/repo/actions/runs" {
+		t.Errorf("request path = %q, want the repo-wide runs endpoint when no workflow selector is set", gotPath)
+	}
+	if gotQuery != "" {
+		t.Errorf("request query = %q, want empty when no filters are set", gotQuery)
+	}
+}
+
+func TestAPIURL_GHES(t *testing.T) {
+	repo := ghrepo.NewWithHost("owner", "repo", "ghe.example.com")
+	got := APIURL(repo, "repos/owner/repo/actions/runs")
+	want := "https://ghe.example.com/api/v3/repos/owner/repo/actions/runs"
+	if got != want {
+		t.Errorf("APIURL = %q, want %q", got, want)
+	}
+}
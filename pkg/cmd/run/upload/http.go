@@ -0,0 +1,184 @@
+package upload
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/run/shared"
+)
+
+type apiPlatform struct {
+	client *http.Client
+	repo   ghrepo.Interface
+
+	retentionDays    int
+	compressionLevel int
+}
+
+func (p *apiPlatform) Upload(runID, name string, files []string) error {
+	archive, err := zipFiles(files, p.compressionLevel)
+	if err != nil {
+		return err
+	}
+
+	path := fmt.Sprintf("repos/%s/actions/runs/%s/artifacts", ghrepo.FullName(p.repo), runID)
+	req, err := http.NewRequest("POST", shared.APIURL(p.repo, path), archive)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/zip")
+	q := req.URL.Query()
+	q.Set("name", name)
+	if p.retentionDays > 0 {
+		q.Set("retention_days", fmt.Sprintf("%d", p.retentionDays))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d while uploading artifact %q", resp.StatusCode, name)
+	}
+
+	return nil
+}
+
+// zipFiles packages the given files into an in-memory zip archive at the
+// requested compression level.
+func zipFiles(files []string, compressionLevel int) (*bytes.Reader, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	zw.RegisterCompressor(zip.Deflate, func(w io.Writer) (io.WriteCloser, error) {
+		return flate.NewWriter(w, compressionLevel)
+	})
+
+	seenNames := map[string]bool{}
+	for _, f := range files {
+		if err := addFileToZip(zw, f, root, seenNames); err != nil {
+			zw.Close()
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+func addFileToZip(zw *zip.Writer, path, root string, seenNames map[string]bool) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return nil
+	}
+
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = filepath.ToSlash(uniqueEntryName(zipEntryName(abs, root), seenNames))
+	header.Method = zip.Deflate
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// zipEntryName picks the name path should be given inside the archive: its
+// path relative to root when it's underneath root (the common case of
+// zipping a build output directory), or just its base name when it falls
+// outside root (e.g. a CI system writing artifacts to a directory the
+// workflow doesn't own, like /tmp/build/out.bin). This avoids ever embedding
+// an absolute local filesystem path, or a "../"-prefixed one, into the zip.
+func zipEntryName(abs, root string) string {
+	rel, err := filepath.Rel(root, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Base(abs)
+	}
+	return rel
+}
+
+// uniqueEntryName disambiguates name against seenNames, which is mutated to
+// record whatever name is returned. Needed because zipEntryName falls back to
+// a bare base name for files outside root, and two such files from different
+// directories (e.g. /tmp/build-a/out.log and /tmp/build-b/out.log) can
+// otherwise collide on the same zip entry, silently dropping one.
+func uniqueEntryName(name string, seenNames map[string]bool) string {
+	if !seenNames[name] {
+		seenNames[name] = true
+		return name
+	}
+
+	ext := filepath.Ext(name)
+	base := name
+	if ext != "" && ext != name {
+		base = strings.TrimSuffix(name, ext)
+	} else {
+		ext = ""
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
+		if !seenNames[candidate] {
+			seenNames[candidate] = true
+			return candidate
+		}
+	}
+}
+
+func walkFiles(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+
+	var files []string
+	err = filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
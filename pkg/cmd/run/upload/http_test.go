@@ -0,0 +1,159 @@
+package upload
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddFileToZip_FileOutsideRootUsesBaseName(t *testing.T) {
+	root := t.TempDir()
+	outside := filepath.Join(t.TempDir(), "out.bin")
+	if err := os.WriteFile(outside, []byte("build output"), 0644); err != nil {
+		t.Fatalf("writing outside file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := addFileToZip(zw, outside, root, map[string]bool{}); err != nil {
+		t.Fatalf("unexpected error adding a file outside root: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading back zip: %v", err)
+	}
+	if len(zr.File) != 1 {
+		t.Fatalf("expected 1 entry in zip, got %d", len(zr.File))
+	}
+	if got := zr.File[0].Name; got != "out.bin" {
+		t.Errorf("entry name = %q, want %q (base name, no absolute path or .. segments)", got, "out.bin")
+	}
+}
+
+func TestAddFileToZip_FileUnderRootUsesRelativeName(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "nested"), 0755); err != nil {
+		t.Fatalf("mkdir nested: %v", err)
+	}
+	inside := filepath.Join(root, "nested", "keep.txt")
+	if err := os.WriteFile(inside, []byte("contents"), 0644); err != nil {
+		t.Fatalf("writing inside file: %v", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := addFileToZip(zw, inside, root, map[string]bool{}); err != nil {
+		t.Fatalf("unexpected error adding file under root: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("closing zip writer: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("reading back zip: %v", err)
+	}
+	if len(zr.File) != 1 {
+		t.Fatalf("expected 1 entry in zip, got %d", len(zr.File))
+	}
+	if got := zr.File[0].Name; got != "nested/keep.txt" {
+		t.Errorf("entry name = %q, want %q (relative to root)", got, "nested/keep.txt")
+	}
+}
+
+func TestZipFiles_DisambiguatesCollidingBaseNames(t *testing.T) {
+	dirA := filepath.Join(t.TempDir(), "build-a")
+	dirB := filepath.Join(t.TempDir(), "build-b")
+	if err := os.MkdirAll(dirA, 0755); err != nil {
+		t.Fatalf("mkdir dirA: %v", err)
+	}
+	if err := os.MkdirAll(dirB, 0755); err != nil {
+		t.Fatalf("mkdir dirB: %v", err)
+	}
+	fileA := filepath.Join(dirA, "out.log")
+	fileB := filepath.Join(dirB, "out.log")
+	if err := os.WriteFile(fileA, []byte("from a"), 0644); err != nil {
+		t.Fatalf("writing fileA: %v", err)
+	}
+	if err := os.WriteFile(fileB, []byte("from b"), 0644); err != nil {
+		t.Fatalf("writing fileB: %v", err)
+	}
+
+	r, err := zipFiles([]string{fileA, fileB}, 6)
+	if err != nil {
+		t.Fatalf("zipFiles: %v", err)
+	}
+
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		t.Fatalf("reading back zip: %v", err)
+	}
+	if len(zr.File) != 2 {
+		t.Fatalf("expected 2 entries in zip, got %d", len(zr.File))
+	}
+
+	names := map[string]bool{}
+	for _, zf := range zr.File {
+		if names[zf.Name] {
+			t.Fatalf("duplicate zip entry name %q: both out.log files collided", zf.Name)
+		}
+		names[zf.Name] = true
+	}
+	if !names["out.log"] || !names["out-2.log"] {
+		t.Errorf("expected entries {out.log, out-2.log}, got %v", names)
+	}
+}
+
+func TestUniqueEntryName_DotfileCollision(t *testing.T) {
+	seen := map[string]bool{}
+	first := uniqueEntryName(".env", seen)
+	second := uniqueEntryName(".env", seen)
+	if first != ".env" {
+		t.Errorf("first = %q, want %q", first, ".env")
+	}
+	if second != ".env-2" {
+		t.Errorf("second = %q, want %q", second, ".env-2")
+	}
+}
+
+func TestZipFiles_ProducesValidArchive(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	if err := os.WriteFile(a, []byte("alpha"), 0644); err != nil {
+		t.Fatalf("writing a.txt: %v", err)
+	}
+	if err := os.WriteFile(b, []byte("beta"), 0644); err != nil {
+		t.Fatalf("writing b.txt: %v", err)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("chdir: %v", err)
+	}
+	defer os.Chdir(cwd)
+
+	r, err := zipFiles([]string{"a.txt", "b.txt"}, 6)
+	if err != nil {
+		t.Fatalf("zipFiles: %v", err)
+	}
+
+	zr, err := zip.NewReader(r, r.Size())
+	if err != nil {
+		t.Fatalf("reading back zip: %v", err)
+	}
+	if len(zr.File) != 2 {
+		t.Fatalf("expected 2 entries in zip, got %d", len(zr.File))
+	}
+}
@@ -0,0 +1,137 @@
+package upload
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/cli/cli/v2/pkg/cmdutil"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"github.com/spf13/cobra"
+)
+
+type UploadOptions struct {
+	IO       *iostreams.IOStreams
+	Platform platform
+
+	RunID string
+	Name  string
+	Paths []string
+
+	RetentionDays    int
+	CompressionLevel int
+}
+
+type platform interface {
+	Upload(runID, name string, files []string) error
+}
+
+func NewCmdUpload(f *cmdutil.Factory, runF func(*UploadOptions) error) *cobra.Command {
+	opts := &UploadOptions{
+		IO: f.IOStreams,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "upload <run-id> <path>...",
+		Short: "Upload local files as artifacts of a workflow run",
+		Long: heredoc.Doc(`
+			Upload one or more local files or directories as artifacts attached to a
+			GitHub Actions workflow run.
+
+			This is useful for attaching artifacts after the fact, mirroring artifacts
+			produced outside of GitHub Actions, or backfilling artifacts from CI systems
+			that don't upload through the actions/upload-artifact runtime.
+
+			Paths may be glob patterns, in which case all matches are included in the
+			uploaded artifact.
+		`),
+		Args: cobra.MinimumNArgs(2),
+		Example: heredoc.Doc(`
+			# Upload dist/ as an artifact named "build" on run 12345
+			$ gh run upload 12345 --name build dist/
+
+			# Upload files matched by a glob
+			$ gh run upload 12345 --name logs "logs/*.log"
+		`),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.RunID = args[0]
+			opts.Paths = args[1:]
+
+			if opts.Name == "" {
+				return cmdutil.FlagErrorf("--name is required")
+			}
+			if opts.CompressionLevel < 0 || opts.CompressionLevel > 9 {
+				return cmdutil.FlagErrorf("--compression-level must be between 0 and 9")
+			}
+
+			httpClient, err := f.HttpClient()
+			if err != nil {
+				return err
+			}
+			baseRepo, err := f.BaseRepo()
+			if err != nil {
+				return err
+			}
+			opts.Platform = &apiPlatform{
+				client:           httpClient,
+				repo:             baseRepo,
+				retentionDays:    opts.RetentionDays,
+				compressionLevel: opts.CompressionLevel,
+			}
+
+			if runF != nil {
+				return runF(opts)
+			}
+			return runUpload(opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Name, "name", "n", "", "Name of the artifact to create")
+	cmd.Flags().IntVar(&opts.RetentionDays, "retention-days", 0, "Number of days to keep the artifact (0 uses the repository default)")
+	cmd.Flags().IntVar(&opts.CompressionLevel, "compression-level", 6, "Zip compression level, 0 (none) to 9 (maximum)")
+
+	return cmd
+}
+
+func runUpload(opts *UploadOptions) error {
+	files, err := expandPaths(opts.Paths)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("no files matched the given paths")
+	}
+
+	opts.IO.StartProgressIndicatorWithLabel(fmt.Sprintf("Uploading %s", opts.Name))
+	defer opts.IO.StopProgressIndicator()
+
+	if err := opts.Platform.Upload(opts.RunID, opts.Name, files); err != nil {
+		return fmt.Errorf("error uploading %s: %w", opts.Name, err)
+	}
+
+	return nil
+}
+
+// expandPaths resolves globs among paths and walks any directories, returning
+// a flat list of regular files to include in the artifact.
+func expandPaths(paths []string) ([]string, error) {
+	var files []string
+	for _, p := range paths {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		if len(matches) == 0 {
+			matches = []string{p}
+		}
+		for _, m := range matches {
+			walked, err := walkFiles(m)
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, walked...)
+		}
+	}
+	return files, nil
+}